@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/db"
+	"github.com/audi70r/scanner-darkly/pkg/scan"
+)
+
+var (
+	dbLogsSince   string
+	dbLogsFlagged bool
+)
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect the local sqlite database",
+}
+
+var dbLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "List stored logs, optionally filtered by time or flagged status",
+	RunE:  dbLogs,
+}
+
+func init() {
+	dbLogsCmd.Flags().StringVar(&dbLogsSince, "since", "", "only show logs created after this RFC3339 timestamp")
+	dbLogsCmd.Flags().BoolVar(&dbLogsFlagged, "flagged", false, "only show logs containing at least one flagged item")
+
+	dbCmd.AddCommand(dbLogsCmd)
+}
+
+func dbLogs(cmd *cobra.Command, args []string) error {
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+
+	var logs []db.Log
+	var err error
+	if dbLogsSince != "" {
+		since, perr := time.Parse(time.RFC3339, dbLogsSince)
+		if perr != nil {
+			return fmt.Errorf("invalid --since timestamp: %w", perr)
+		}
+		logs, err = db.ListLogsSince(since)
+	} else {
+		logs, err = db.ListLogs()
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, l := range logs {
+		if dbLogsFlagged && !scan.IsFlagged(l.Content) {
+			continue
+		}
+		fmt.Printf("%d\t%s\tmachine=%d\n", l.ID, l.Date, l.MachineID)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/pkg/hub"
+)
+
+var hubRemote string
+
+var hubCmd = &cobra.Command{
+	Use:   "hub",
+	Short: "Manage community-maintained check collections",
+}
+
+var hubUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Refresh the view of what's available upstream",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		entries, err := hub.Update(hubRemote)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			fmt.Println(e)
+		}
+		return nil
+	},
+}
+
+var hubListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed collections",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cols, err := hub.List()
+		if err != nil {
+			return err
+		}
+		for _, col := range cols {
+			fmt.Printf("%s (%s) - %d checks\n", col.Name, col.Version, len(col.Checks))
+		}
+		return nil
+	},
+}
+
+var hubInstallCmd = &cobra.Command{
+	Use:   "install <collection>",
+	Short: "Install a collection from the hub",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		col, err := hub.Install(hubRemote, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("installed %s (%s)\n", col.Name, col.Version)
+		return nil
+	},
+}
+
+var hubUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade every installed collection to its latest version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		upgraded, err := hub.Upgrade(hubRemote)
+		if err != nil {
+			return err
+		}
+		for _, col := range upgraded {
+			fmt.Printf("upgraded %s to %s\n", col.Name, col.Version)
+		}
+		return nil
+	},
+}
+
+func init() {
+	hubCmd.PersistentFlags().StringVar(&hubRemote, "remote", "", "GitHub \"owner/repo\" to fetch hub bundles from (default: "+hub.DefaultRemote+")")
+	hubCmd.AddCommand(hubUpdateCmd, hubListCmd, hubInstallCmd, hubUpgradeCmd)
+}
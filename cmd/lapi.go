@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/db"
+	"github.com/audi70r/scanner-darkly/pkg/config"
+	"github.com/audi70r/scanner-darkly/pkg/lapi"
+)
+
+var (
+	lapiURL   string
+	lapiToken string
+	lapiName  string
+	lapiAddr  string
+)
+
+var lapiCmd = &cobra.Command{
+	Use:   "lapi",
+	Short: "Act as a LAPI agent or server",
+}
+
+var lapiRegisterCmd = &cobra.Command{
+	Use:   "register",
+	Short: "Exchange a bootstrap token for a long-lived API key",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		apiKey, err := lapi.Register(lapiURL, lapiName, lapiToken)
+		if err != nil {
+			return err
+		}
+
+		path, err := resolveConfigPath()
+		if err != nil {
+			return err
+		}
+		cfg, err := config.Load(path)
+		if err != nil {
+			return err
+		}
+		cfg.LapiURL = lapiURL
+		cfg.LapiAPIKey = apiKey
+		if err := config.Save(path, cfg); err != nil {
+			return err
+		}
+
+		fmt.Printf("enrolled, API key saved to %s\n", path)
+		return nil
+	},
+}
+
+var lapiServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the LAPI HTTP endpoints",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.InitDB(); err != nil {
+			return err
+		}
+		return lapi.NewServer().ListenAndServe(lapiAddr)
+	},
+}
+
+func init() {
+	lapiRegisterCmd.Flags().StringVar(&lapiURL, "url", "", "LAPI base URL")
+	lapiRegisterCmd.Flags().StringVar(&lapiToken, "token", "", "bootstrap token")
+	lapiRegisterCmd.Flags().StringVar(&lapiName, "name", "", "name to enroll this machine as")
+
+	lapiServeCmd.Flags().StringVar(&lapiAddr, "addr", ":8080", "address to listen on")
+
+	lapiCmd.AddCommand(lapiRegisterCmd, lapiServeCmd)
+}
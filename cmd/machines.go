@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/db"
+	"github.com/audi70r/scanner-darkly/pkg/lapi"
+)
+
+var machinesCmd = &cobra.Command{
+	Use:   "machines",
+	Short: "Manage machines enrolled against this LAPI",
+}
+
+var machinesAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Generate a bootstrap token for a new machine",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := db.InitDB(); err != nil {
+			return err
+		}
+
+		token, err := lapi.NewBootstrapToken()
+		if err != nil {
+			return err
+		}
+
+		if _, err := db.CreateMachine(args[0], token); err != nil {
+			return fmt.Errorf("creating machine: %w", err)
+		}
+
+		fmt.Printf("machine %q added, bootstrap token: %s\n", args[0], token)
+		return nil
+	},
+}
+
+func init() {
+	machinesCmd.AddCommand(machinesAddCmd)
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/db"
+	"github.com/audi70r/scanner-darkly/pkg/scan"
+)
+
+var (
+	reportFormat string
+	reportOut    string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Inspect stored reports",
+}
+
+var reportListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored reports",
+	RunE:  reportList,
+}
+
+var reportShowCmd = &cobra.Command{
+	Use:   "show <id>",
+	Short: "Show a single stored report",
+	Args:  cobra.ExactArgs(1),
+	RunE:  reportShow,
+}
+
+var reportExportCmd = &cobra.Command{
+	Use:   "export <id>",
+	Short: "Export a stored report as json, yaml, sarif or html",
+	Args:  cobra.ExactArgs(1),
+	RunE:  reportExport,
+}
+
+func init() {
+	reportExportCmd.Flags().StringVar(&reportFormat, "format", "json", "output format: json, yaml, sarif, html")
+	reportExportCmd.Flags().StringVar(&reportOut, "out", "", "write to this file instead of stdout")
+
+	reportCmd.AddCommand(reportListCmd, reportShowCmd, reportExportCmd)
+}
+
+func reportList(cmd *cobra.Command, args []string) error {
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+	logs, err := db.ListLogs()
+	if err != nil {
+		return err
+	}
+	for _, l := range logs {
+		fmt.Printf("%d\t%s\tmachine=%d\n", l.ID, l.Date, l.MachineID)
+	}
+	return nil
+}
+
+func reportShow(cmd *cobra.Command, args []string) error {
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	log, err := db.GetLog(id)
+	if err != nil {
+		return err
+	}
+	fmt.Println(log.Content)
+	return nil
+}
+
+func reportExport(cmd *cobra.Command, args []string) error {
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+	id, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	log, err := db.GetLog(id)
+	if err != nil {
+		return err
+	}
+
+	var report scan.Report
+	if err := json.Unmarshal([]byte(log.Content), &report); err != nil {
+		return fmt.Errorf("parse stored report: %w", err)
+	}
+
+	out, err := scan.Export(report, reportFormat)
+	if err != nil {
+		return err
+	}
+
+	if reportOut == "" {
+		fmt.Println(string(out))
+		return nil
+	}
+	return os.WriteFile(reportOut, out, 0644)
+}
+
+func parseID(s string) (uint, error) {
+	var id uint
+	_, err := fmt.Sscanf(s, "%d", &id)
+	if err != nil {
+		return 0, fmt.Errorf("invalid report id %q", s)
+	}
+	return id, nil
+}
@@ -0,0 +1,64 @@
+// Package cmd implements diligent's cscli-style command tree: run, report,
+// db, hub, machines, lapi and support, all sharing one config loader so API keys,
+// the model name and per-run limits are configurable per-invocation instead
+// of being package constants.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/pkg/config"
+)
+
+var configPath string
+
+var rootCmd = &cobra.Command{
+	Use:   "diligent",
+	Short: "AI-assisted host security scanner",
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "path to config.yaml (default ~/.config/diligent/config.yaml)")
+
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(dbCmd)
+	rootCmd.AddCommand(hubCmd)
+	rootCmd.AddCommand(machinesCmd)
+	rootCmd.AddCommand(lapiCmd)
+	rootCmd.AddCommand(supportCmd)
+}
+
+// Execute runs the diligent CLI; it's the only exported entry point main.go
+// needs to call.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// resolveConfigPath returns the --config flag value, or config.Path()'s
+// default when it wasn't set.
+func resolveConfigPath() (string, error) {
+	if configPath != "" {
+		return configPath, nil
+	}
+	return config.Path()
+}
+
+// loadConfig resolves the configured path (or the default) and loads it.
+func loadConfig() (config.Config, error) {
+	path, err := resolveConfigPath()
+	if err != nil {
+		return config.Config{}, err
+	}
+	return config.Load(path)
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/db"
+	hostcontext "github.com/audi70r/scanner-darkly/pkg/context"
+	"github.com/audi70r/scanner-darkly/pkg/hub"
+	"github.com/audi70r/scanner-darkly/pkg/lapi"
+	"github.com/audi70r/scanner-darkly/pkg/scan"
+)
+
+var (
+	runOS      string
+	runTags    []string
+	runCheckID string
+)
+
+var runCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the installed checks for this (or a given) OS and analyze them with GPT",
+	RunE:  runRun,
+}
+
+func init() {
+	runCmd.Flags().StringVar(&runOS, "os", "", "OS to run checks for (default: detected OS)")
+	runCmd.Flags().StringSliceVar(&runTags, "tags", nil, "only run checks carrying any of these tags")
+	runCmd.Flags().StringVar(&runCheckID, "check-id", "", "only run the check whose command matches this string")
+}
+
+func runRun(cmd *cobra.Command, args []string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if cfg.OpenAIAPIKey == "" {
+		fatalf("OpenAI API key is not set")
+	}
+
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+
+	targetOS := runOS
+	if targetOS == "" {
+		targetOS = scan.DetectOS()
+	}
+
+	checks, err := hub.LoadInstalled(targetOS)
+	if err != nil {
+		return err
+	}
+	checks = filterChecks(checks, runTags, runCheckID)
+	if len(checks) == 0 {
+		fatalf("no checks installed for %s (after filtering); run `diligent hub install <collection>` first", targetOS)
+	}
+
+	contextCfg, err := hostcontext.LoadConfig("context.yaml")
+	if err != nil {
+		return err
+	}
+
+	client := openai.NewClient(cfg.OpenAIAPIKey)
+	report := scan.Run(client, checks, scan.Options{
+		Model:          cfg.Model,
+		MaxFollowups:   cfg.MaxFollowups,
+		CommandTimeout: cfg.CommandTimeout,
+		ContextConfig:  contextCfg,
+	})
+
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := db.CreateLog(time.Now(), string(reportBytes)); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile("report.json", reportBytes, 0644); err != nil {
+		return err
+	}
+
+	if cfg.LapiURL != "" {
+		if err := lapi.PushReport(cfg.LapiURL, cfg.LapiAPIKey, reportBytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filterChecks narrows checks down to those matching tags and/or checkID,
+// leaving the slice untouched when both filters are empty.
+func filterChecks(checks []hub.Check, tags []string, checkID string) []hub.Check {
+	if len(tags) == 0 && checkID == "" {
+		return checks
+	}
+
+	var filtered []hub.Check
+	for _, chk := range checks {
+		if checkID != "" && !strings.Contains(chk.Command, checkID) {
+			continue
+		}
+		if len(tags) > 0 && !hasAnyTag(chk.Tags, tags) {
+			continue
+		}
+		filtered = append(filtered, chk)
+	}
+	return filtered
+}
+
+func hasAnyTag(checkTags, wanted []string) bool {
+	for _, t := range checkTags {
+		for _, w := range wanted {
+			if t == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/audi70r/scanner-darkly/db"
+	"github.com/audi70r/scanner-darkly/pkg/hub"
+)
+
+const supportDumpReportLimit = 10
+
+var (
+	supportStdout bool
+	supportOut    string
+)
+
+var supportCmd = &cobra.Command{
+	Use:   "support",
+	Short: "Collect diagnostics for filing an issue",
+}
+
+var supportDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Bundle the sqlite db, recent reports, redacted config, OS info and hub index into one archive",
+	RunE:  supportDump,
+}
+
+func init() {
+	supportDumpCmd.Flags().BoolVar(&supportStdout, "stdout", false, "write the archive to stdout instead of a file")
+	supportDumpCmd.Flags().StringVar(&supportOut, "out", "diligent-support.zip", "archive path to write")
+
+	supportCmd.AddCommand(supportDumpCmd)
+}
+
+func supportDump(cmd *cobra.Command, args []string) error {
+	if err := db.InitDB(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := addDBFile(zw); err != nil {
+		return err
+	}
+	if err := addRecentReports(zw); err != nil {
+		return err
+	}
+	if err := addRedactedConfig(zw); err != nil {
+		return err
+	}
+	if err := addOSInfo(zw); err != nil {
+		return err
+	}
+	if err := addHubIndex(zw); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("finalize support dump: %w", err)
+	}
+
+	if supportStdout {
+		_, err := io.Copy(os.Stdout, &buf)
+		return err
+	}
+	return os.WriteFile(supportOut, buf.Bytes(), 0644)
+}
+
+func addDBFile(zw *zip.Writer) error {
+	data, err := os.ReadFile("test.db")
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read database: %w", err)
+	}
+	return writeZipEntry(zw, "test.db", data)
+}
+
+func addRecentReports(zw *zip.Writer) error {
+	logs, err := db.ListLogs()
+	if err != nil {
+		return err
+	}
+	if len(logs) > supportDumpReportLimit {
+		logs = logs[:supportDumpReportLimit]
+	}
+	for _, l := range logs {
+		if err := writeZipEntry(zw, fmt.Sprintf("reports/%d.json", l.ID), []byte(l.Content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addRedactedConfig(zw *zip.Writer) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	cfg.OpenAIAPIKey = redact(cfg.OpenAIAPIKey)
+	cfg.LapiAPIKey = redact(cfg.LapiAPIKey)
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeZipEntry(zw, "config.json", data)
+}
+
+func addOSInfo(zw *zip.Writer) error {
+	info := map[string]string{
+		"goos":   runtime.GOOS,
+		"goarch": runtime.GOARCH,
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeZipEntry(zw, "os.json", data)
+}
+
+func addHubIndex(zw *zip.Writer) error {
+	cols, err := hub.List()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cols, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeZipEntry(zw, "hub-index.json", data)
+}
+
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s in archive: %w", name, err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "<redacted>"
+}
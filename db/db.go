@@ -17,7 +17,7 @@ func InitDB() error {
 	}
 
 	// Auto migrate your models here
-	err = DB.AutoMigrate(&Log{})
+	err = DB.AutoMigrate(&Log{}, &Machine{})
 	if err != nil {
 		return err
 	}
@@ -27,9 +27,11 @@ func InitDB() error {
 
 type Log struct {
 	gorm.Model
-	ID      uint
-	Date    string
-	Content string
+	ID        uint
+	Date      string
+	Content   string
+	MachineID uint
+	Machine   Machine
 }
 
 func CreateLog(date time.Time, content string) error {
@@ -40,3 +42,28 @@ func CreateLog(date time.Time, content string) error {
 	result := DB.Create(&log)
 	return result.Error
 }
+
+// CreateMachineLog stores a report submitted by a remote scanner agent.
+func CreateMachineLog(date time.Time, content string, machineID uint) error {
+	log := Log{
+		Date:      date.Format("2006-01-02 15:04:05"),
+		Content:   content,
+		MachineID: machineID,
+	}
+	result := DB.Create(&log)
+	return result.Error
+}
+
+// GetLog fetches a single stored report by its ID.
+func GetLog(id uint) (Log, error) {
+	var log Log
+	result := DB.First(&log, id)
+	return log, result.Error
+}
+
+// ListLogsSince returns every stored report created at or after since.
+func ListLogsSince(since time.Time) ([]Log, error) {
+	var logs []Log
+	result := DB.Where("created_at >= ?", since).Order("created_at desc").Find(&logs)
+	return logs, result.Error
+}
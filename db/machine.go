@@ -0,0 +1,83 @@
+package db
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Machine represents a scanner agent enrolled with this instance acting as
+// a LAPI (local API). An agent enrolls once via a bootstrap token, which is
+// exchanged for a long-lived APIKey used to authenticate subsequent report
+// submissions.
+type Machine struct {
+	gorm.Model
+	ID             uint
+	Name           string `gorm:"uniqueIndex"`
+	BootstrapToken string
+	APIKey         string
+	Enrolled       bool
+}
+
+// CreateMachine registers a new machine with the given bootstrap token and
+// returns the created record.
+func CreateMachine(name, bootstrapToken string) (Machine, error) {
+	machine := Machine{
+		Name:           name,
+		BootstrapToken: bootstrapToken,
+	}
+	result := DB.Create(&machine)
+	return machine, result.Error
+}
+
+// FindMachineByBootstrapToken looks up a not-yet-enrolled machine by the
+// bootstrap token issued to it.
+func FindMachineByBootstrapToken(token string) (Machine, error) {
+	var machine Machine
+	result := DB.Where("bootstrap_token = ? AND enrolled = ?", token, false).First(&machine)
+	return machine, result.Error
+}
+
+// FindMachineByName looks up a machine by its enrollment name.
+func FindMachineByName(name string) (Machine, error) {
+	var machine Machine
+	result := DB.Where("name = ?", name).First(&machine)
+	return machine, result.Error
+}
+
+// FindMachineByAPIKey looks up an enrolled machine by its long-lived API key.
+func FindMachineByAPIKey(apiKey string) (Machine, error) {
+	var machine Machine
+	result := DB.Where("api_key = ? AND enrolled = ?", apiKey, true).First(&machine)
+	return machine, result.Error
+}
+
+// EnrollMachine marks a machine as enrolled and assigns its long-lived
+// API key, consuming the bootstrap token in the process.
+func EnrollMachine(id uint, apiKey string) error {
+	result := DB.Model(&Machine{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"api_key":  apiKey,
+		"enrolled": true,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("machine not found")
+	}
+	return nil
+}
+
+// ListMachineLogs returns the reports submitted by a given machine.
+func ListMachineLogs(machineID uint) ([]Log, error) {
+	var logs []Log
+	result := DB.Where("machine_id = ?", machineID).Order("created_at desc").Find(&logs)
+	return logs, result.Error
+}
+
+// ListLogs returns every stored report, local and machine-submitted alike.
+func ListLogs() ([]Log, error) {
+	var logs []Log
+	result := DB.Order("created_at desc").Find(&logs)
+	return logs, result.Error
+}
@@ -0,0 +1,198 @@
+// Package chunker splits command output into relevance-scored chunks
+// instead of hard-truncating it. A fixed character cutoff silently
+// discards potentially critical evidence - e.g. only the first 20
+// processes get analyzed even when the suspicious one is #21. Splitting
+// along natural boundaries and scoring each piece lets the highest-signal
+// chunks reach GPT regardless of where in the output they land.
+package chunker
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Chunk is one scored piece of a larger command output.
+type Chunk struct {
+	Index int     `json:"index"`
+	Text  string  `json:"-"`
+	Score float64 `json:"score"`
+}
+
+var nonAppleBundleID = regexp.MustCompile(`\b[a-z0-9]+\.[a-z0-9]+\.(?:[a-z0-9]+\.)*[a-z0-9]+\b`)
+
+// Split breaks output into chunks of at most chunkSize characters, first
+// at "---" record separators and then between individual lines. Lines are
+// never split, so a single line longer than chunkSize becomes a chunk of
+// its own that exceeds chunkSize.
+func Split(output string, chunkSize int) []string {
+	var records []string
+	if strings.Contains(output, "\n---\n") {
+		records = strings.Split(output, "\n---\n")
+	} else {
+		records = []string{output}
+	}
+
+	var chunks []string
+	for _, record := range records {
+		chunks = append(chunks, splitLines(record, chunkSize)...)
+	}
+	return chunks
+}
+
+// splitLines greedily packs lines into chunks up to chunkSize characters;
+// a line longer than chunkSize is kept whole as its own chunk.
+func splitLines(text string, chunkSize int) []string {
+	lines := strings.Split(text, "\n")
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, line := range lines {
+		if current.Len()+len(line)+1 > chunkSize {
+			flush()
+		}
+		if current.Len() > 0 {
+			current.WriteByte('\n')
+		}
+		current.WriteString(line)
+	}
+	flush()
+
+	return chunks
+}
+
+// Score rates how likely a chunk is to contain something worth GPT's
+// attention: keyword hits against the check's own prompt, entropy (high
+// entropy can indicate encoded payloads), root-owned lines, and bundle
+// IDs that don't look like Apple's.
+func Score(chunk string, keywords []string) float64 {
+	lower := strings.ToLower(chunk)
+
+	var score float64
+	for _, kw := range keywords {
+		score += float64(strings.Count(lower, strings.ToLower(kw)))
+	}
+
+	score += entropy(chunk) / 2
+
+	if strings.Contains(chunk, " 0 ") || strings.Contains(lower, "uid=0") || strings.Contains(lower, "root ") {
+		score++
+	}
+
+	for _, match := range nonAppleBundleID.FindAllString(chunk, -1) {
+		if !strings.HasPrefix(match, "com.apple.") {
+			score += 0.5
+		}
+	}
+
+	return score
+}
+
+// entropy computes the Shannon entropy of chunk's bytes, a cheap proxy for
+// "does this look like encoded or randomized data".
+func entropy(chunk string) float64 {
+	if chunk == "" {
+		return 0
+	}
+
+	var counts [256]int
+	for i := 0; i < len(chunk); i++ {
+		counts[chunk[i]]++
+	}
+
+	var h float64
+	n := float64(len(chunk))
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+// Keywords extracts the meaningful words from a check's prompt, used to
+// score its own chunks against what it's actually looking for.
+func Keywords(prompt string) []string {
+	fields := strings.Fields(prompt)
+	var keywords []string
+	for _, f := range fields {
+		f = strings.Trim(f, ".,;:()\"'")
+		if len(f) > 4 {
+			keywords = append(keywords, f)
+		}
+	}
+	return keywords
+}
+
+// ScoreAll splits output into chunks and scores every one against
+// keywords, without discarding any of them. Callers that need to audit
+// why a chunk wasn't selected (see SelectTopK) should keep this full set
+// around rather than only the selected subset.
+func ScoreAll(output string, keywords []string, chunkSize int) []Chunk {
+	texts := Split(output, chunkSize)
+
+	chunks := make([]Chunk, len(texts))
+	for i, t := range texts {
+		chunks[i] = Chunk{Index: i, Text: t, Score: Score(t, keywords)}
+	}
+	return chunks
+}
+
+// SelectTopK returns the k highest-scoring chunks from an already-scored
+// set, in their original order.
+func SelectTopK(chunks []Chunk, k int) []Chunk {
+	if len(chunks) <= k {
+		return chunks
+	}
+
+	ranked := make([]Chunk, len(chunks))
+	copy(ranked, chunks)
+	// simple selection of the k highest scores, keeping original order
+	// among the selected chunks for readability downstream.
+	threshold := kthHighestScore(ranked, k)
+
+	var selected []Chunk
+	for _, c := range chunks {
+		if c.Score >= threshold && len(selected) < k {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// TopK splits output, scores every chunk against keywords, and returns the
+// k highest-scoring chunks in their original order.
+func TopK(output string, keywords []string, chunkSize, k int) []Chunk {
+	return SelectTopK(ScoreAll(output, keywords, chunkSize), k)
+}
+
+// kthHighestScore returns the score of the k-th highest chunk.
+func kthHighestScore(chunks []Chunk, k int) float64 {
+	scores := make([]float64, len(chunks))
+	for i, c := range chunks {
+		scores[i] = c.Score
+	}
+	// insertion sort descending; chunk counts per check output are small.
+	for i := 1; i < len(scores); i++ {
+		v := scores[i]
+		j := i - 1
+		for j >= 0 && scores[j] < v {
+			scores[j+1] = scores[j]
+			j--
+		}
+		scores[j+1] = v
+	}
+	if k-1 < len(scores) {
+		return scores[k-1]
+	}
+	return scores[len(scores)-1]
+}
@@ -0,0 +1,61 @@
+package chunker
+
+import "testing"
+
+func TestScoreRewardsKeywordHits(t *testing.T) {
+	keywords := []string{"suspicious", "backdoor"}
+
+	withHits := Score("this process looks suspicious and may be a backdoor", keywords)
+	withoutHits := Score("this is an ordinary line of log output", keywords)
+
+	if withHits <= withoutHits {
+		t.Errorf("Score with keyword hits (%.2f) should exceed score without (%.2f)", withHits, withoutHits)
+	}
+}
+
+func TestScoreRewardsRootOwnership(t *testing.T) {
+	root := Score("uid=0(root) gid=0(root) groups=0(root)", nil)
+	user := Score("uid=501(alice) gid=20(staff) groups=20(staff)", nil)
+
+	if root <= user {
+		t.Errorf("Score for a root-owned line (%.2f) should exceed a non-root line (%.2f)", root, user)
+	}
+}
+
+func TestTopKKeepsHighestScoringChunksInOriginalOrder(t *testing.T) {
+	output := "first chunk, nothing interesting\n---\nsecond chunk mentions a backdoor\n---\nthird chunk, also plain"
+	chunks := TopK(output, []string{"backdoor"}, 1000, 1)
+
+	if len(chunks) != 1 {
+		t.Fatalf("expected exactly 1 chunk, got %d", len(chunks))
+	}
+	if chunks[0].Index != 1 {
+		t.Errorf("expected the chunk mentioning the keyword (index 1) to win, got index %d", chunks[0].Index)
+	}
+}
+
+func TestTopKReturnsEverythingWhenUnderK(t *testing.T) {
+	chunks := TopK("just one short chunk", []string{"anything"}, 1000, 4)
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+}
+
+func TestKeywordsDropsShortWords(t *testing.T) {
+	got := Keywords("is the process a backdoor or rootkit?")
+	for _, kw := range got {
+		if len(kw) <= 4 {
+			t.Errorf("Keywords should drop short words, got %q", kw)
+		}
+	}
+
+	found := false
+	for _, kw := range got {
+		if kw == "backdoor" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected Keywords to include %q, got %v", "backdoor", got)
+	}
+}
@@ -0,0 +1,100 @@
+// Package config loads diligent's shared configuration from
+// ~/.config/diligent/config.yaml, so API keys, the model name and the
+// various per-run limits are configurable per-invocation instead of being
+// package constants.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting shared across diligent's commands.
+type Config struct {
+	OpenAIAPIKey   string        `yaml:"openai_api_key"`
+	Model          string        `yaml:"model"`
+	MaxFollowups   int           `yaml:"max_followups"`
+	CommandTimeout time.Duration `yaml:"command_timeout"`
+	LapiURL        string        `yaml:"lapi_url"`
+	LapiAPIKey     string        `yaml:"lapi_api_key"`
+}
+
+// Defaults mirrors the constants diligent used before configuration was
+// made file-based.
+func Defaults() Config {
+	return Config{
+		Model:          "gpt-4o-mini-2024-07-18",
+		MaxFollowups:   5,
+		CommandTimeout: 10 * time.Second,
+	}
+}
+
+// Path returns the default config file location, honoring
+// $XDG_CONFIG_HOME and falling back to ~/.config.
+func Path() (string, error) {
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		return filepath.Join(configHome, "diligent", "config.yaml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "diligent", "config.yaml"), nil
+}
+
+// Load reads and parses the config file at path, overlaying it onto the
+// defaults. A missing file is not an error - it simply yields the
+// defaults, overridden by whatever environment variables are set.
+func Load(path string) (Config, error) {
+	cfg := Defaults()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return applyEnv(cfg), nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config: %w", err)
+	}
+
+	return applyEnv(cfg), nil
+}
+
+// Save writes cfg to path as YAML, creating the parent directory if
+// needed. It's used to persist values obtained at runtime, such as the
+// API key issued by `diligent lapi register`, back into the config file.
+func Save(path string, cfg Config) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("marshal config: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// applyEnv lets environment variables override whatever config.yaml set,
+// which keeps CI and one-off invocations from needing a config file at all.
+func applyEnv(cfg Config) Config {
+	if v := os.Getenv("OPENAI_API_KEY"); v != "" {
+		cfg.OpenAIAPIKey = v
+	}
+	if v := os.Getenv("DILIGENT_LAPI_URL"); v != "" {
+		cfg.LapiURL = v
+	}
+	if v := os.Getenv("DILIGENT_LAPI_API_KEY"); v != "" {
+		cfg.LapiAPIKey = v
+	}
+	return cfg
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "diligent", "config.yaml")
+
+	cfg := Defaults()
+	cfg.LapiURL = "https://lapi.example.test"
+	cfg.LapiAPIKey = "secret-key"
+
+	if err := Save(path, cfg); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if loaded.LapiURL != cfg.LapiURL {
+		t.Errorf("LapiURL = %q, want %q", loaded.LapiURL, cfg.LapiURL)
+	}
+	if loaded.LapiAPIKey != cfg.LapiAPIKey {
+		t.Errorf("LapiAPIKey = %q, want %q", loaded.LapiAPIKey, cfg.LapiAPIKey)
+	}
+	if loaded.Model != cfg.Model {
+		t.Errorf("Model = %q, want %q", loaded.Model, cfg.Model)
+	}
+}
@@ -0,0 +1,73 @@
+package context
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule exposes a set of context keys to checks carrying any of Tags.
+type Rule struct {
+	Tags    []string `yaml:"tags"`
+	Include []string `yaml:"include"`
+}
+
+// Config is the parsed form of context.yaml: per-tag rules plus a default
+// fallback for checks that match no rule.
+type Config struct {
+	Rules   []Rule   `yaml:"rules"`
+	Default []string `yaml:"default"`
+}
+
+// LoadConfig reads and parses a context.yaml file. A missing file is not
+// an error: it yields a zero-value Config, which KeysForTags treats as
+// "expose nothing beyond the default".
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("read context config: %w", err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse context config: %w", err)
+	}
+	return cfg, nil
+}
+
+// KeysForTags returns the union of context keys exposed to a check
+// carrying any of the given tags, falling back to Config.Default when no
+// rule matches.
+func (c Config) KeysForTags(tags []string) []string {
+	tagSet := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		tagSet[t] = true
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	matched := false
+	for _, rule := range c.Rules {
+		for _, t := range rule.Tags {
+			if tagSet[t] {
+				matched = true
+				for _, k := range rule.Include {
+					if !seen[k] {
+						seen[k] = true
+						keys = append(keys, k)
+					}
+				}
+				break
+			}
+		}
+	}
+
+	if !matched {
+		return c.Default
+	}
+	return keys
+}
@@ -0,0 +1,141 @@
+// Package context gathers structured host context once per run - hostname,
+// kernel, OS version, the primary user, public IP, uptime, logged-in users,
+// timezone and disk-encryption state - so it can be injected into GPT
+// prompts and stored alongside each AnalysisItem. This mirrors how
+// CrowdSec compiles a console-context file from hub definitions: it gives
+// the model enough environmental grounding to tell a genuine anomaly from
+// something that's merely unfamiliar (e.g. the primary user logging in).
+package context
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// publicIPTimeout bounds how long Gather will wait on the public-IP
+// lookup, so a slow or unreachable network doesn't stall every run before
+// a single check has executed.
+const publicIPTimeout = 3 * time.Second
+
+// Info holds every host context value this package knows how to gather.
+// It is compiled down to a map[string]string before use so it can be
+// filtered per-check and attached to AnalysisItem without a dependency on
+// this package's types.
+type Info struct {
+	Hostname       string
+	Kernel         string
+	OSVersion      string
+	PrimaryUser    string
+	PublicIP       string
+	Uptime         string
+	LoggedInUsers  []string
+	Timezone       string
+	DiskEncryption string
+}
+
+// Gather collects host context using whatever commands are available on
+// the current OS. Individual collectors that fail are left blank rather
+// than aborting the whole gather.
+func Gather() Info {
+	info := Info{
+		Hostname:      output("hostname"),
+		Kernel:        output("uname", "-r"),
+		PrimaryUser:   output("whoami"),
+		Timezone:      time.Now().Format("MST -07:00"),
+		LoggedInUsers: loggedInUsers(),
+		PublicIP:      publicIP(),
+		Uptime:        output("uptime"),
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		info.OSVersion = output("sw_vers", "-productVersion")
+		info.DiskEncryption = output("fdesetup", "status")
+	case "linux":
+		info.OSVersion = output("lsb_release", "-ds")
+	}
+
+	return info
+}
+
+// Compile flattens Info into a map, keyed the same way context.yaml rules
+// reference it (see Config).
+func (i Info) Compile() map[string]string {
+	return map[string]string{
+		"hostname":        i.Hostname,
+		"kernel":          i.Kernel,
+		"os_version":      i.OSVersion,
+		"primary_user":    i.PrimaryUser,
+		"public_ip":       i.PublicIP,
+		"uptime":          i.Uptime,
+		"logged_in_users": strings.Join(i.LoggedInUsers, ", "),
+		"timezone":        i.Timezone,
+		"disk_encryption": i.DiskEncryption,
+	}
+}
+
+// Subset returns only the requested keys from a compiled context map,
+// dropping keys that aren't present or are empty. The result is a map, so
+// it carries no key order.
+func Subset(compiled map[string]string, keys []string) map[string]string {
+	subset := make(map[string]string, len(keys))
+	for _, k := range keys {
+		if v, ok := compiled[k]; ok && v != "" {
+			subset[k] = v
+		}
+	}
+	return subset
+}
+
+func output(name string, args ...string) string {
+	out, err := exec.Command(name, args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func loggedInUsers() []string {
+	out := output("who")
+	if out == "" {
+		return nil
+	}
+	var users []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if !seen[fields[0]] {
+			seen[fields[0]] = true
+			users = append(users, fields[0])
+		}
+	}
+	return users
+}
+
+func publicIP() string {
+	ctx, cancel := context.WithTimeout(context.Background(), publicIPTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.ipify.org", nil)
+	if err != nil {
+		return ""
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(body))
+}
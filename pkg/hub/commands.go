@@ -0,0 +1,110 @@
+package hub
+
+import (
+	"fmt"
+)
+
+// Update fetches the upstream index and returns what's available, without
+// installing or caching anything, mirroring `diligent hub update`.
+func Update(remote string) ([]indexEntry, error) {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+	return fetchIndex(remote)
+}
+
+// List reports every installed collection along with its version.
+func List() ([]Collection, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	names, err := installedCollections(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var cols []Collection
+	for _, name := range names {
+		col, err := readCollection(cacheDir, name)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// Install downloads, verifies and caches the named collection.
+func Install(remote, name string) (Collection, error) {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+	entries, err := fetchIndex(remote)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	for _, entry := range entries {
+		if entry.Name != name {
+			continue
+		}
+		col, err := downloadBundle(entry)
+		if err != nil {
+			return Collection{}, err
+		}
+		cacheDir, err := CacheDir()
+		if err != nil {
+			return Collection{}, err
+		}
+		if err := writeCollection(cacheDir, col); err != nil {
+			return Collection{}, err
+		}
+		return col, nil
+	}
+
+	return Collection{}, fmt.Errorf("collection %q not found in hub index", name)
+}
+
+// Upgrade reinstalls every currently-installed collection at its latest
+// published version, skipping any whose digest hasn't changed.
+func Upgrade(remote string) ([]Collection, error) {
+	if remote == "" {
+		remote = DefaultRemote
+	}
+	installed, err := List()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := fetchIndex(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]indexEntry, len(entries))
+	for _, entry := range entries {
+		byName[entry.Name] = entry
+	}
+
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var upgraded []Collection
+	for _, col := range installed {
+		entry, ok := byName[col.Name]
+		if !ok || entry.Digest == col.Digest {
+			continue
+		}
+		newCol, err := downloadBundle(entry)
+		if err != nil {
+			return upgraded, err
+		}
+		if err := writeCollection(cacheDir, newCol); err != nil {
+			return upgraded, err
+		}
+		upgraded = append(upgraded, newCol)
+	}
+	return upgraded, nil
+}
@@ -0,0 +1,227 @@
+// Package hub manages community-maintained check bundles for diligent.
+//
+// A hub item is a YAML file describing a single check (command, prompt,
+// target OS, tags, severity and optional dependencies). Items are grouped
+// into collections by theme (persistence, network, usb, ...) and published
+// as versioned bundles in a remote git repository, by default
+// github.com/audi70r/diligent-hub. Bundles are fetched, verified against a
+// published SHA256 digest, and cached locally so `diligent run` can load
+// checks without recompiling the binary.
+package hub
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fetchTimeout bounds how long a single hub network call (index or bundle
+// fetch) is allowed to take, so `hub update|install|upgrade` can't hang
+// forever against a slow or unresponsive remote.
+const fetchTimeout = 10 * time.Second
+
+// DefaultRemote is the GitHub "owner/repo" hub bundles are published from.
+const DefaultRemote = "audi70r/diligent-hub"
+
+// IndexFile is the name of the bundle index fetched from the remote.
+const IndexFile = "index.json"
+
+// Check is a single detection shipped by a hub collection.
+type Check struct {
+	Command      string   `yaml:"command" json:"command"`
+	Prompt       string   `yaml:"prompt" json:"prompt"`
+	OS           string   `yaml:"os" json:"os"`
+	Tags         []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Severity     string   `yaml:"severity,omitempty" json:"severity,omitempty"`
+	DependsOn    []string `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
+	Capabilities []string `yaml:"capabilities,omitempty" json:"capabilities,omitempty"`
+	Allowlist    []string `yaml:"allowlist,omitempty" json:"allowlist,omitempty"`
+	Collection   string   `yaml:"-" json:"collection"`
+}
+
+// Collection groups related checks, e.g. "persistence" or "usb".
+type Collection struct {
+	Name    string  `yaml:"name" json:"name"`
+	Version string  `yaml:"version" json:"version"`
+	Digest  string  `yaml:"digest" json:"digest"`
+	Checks  []Check `yaml:"checks" json:"checks"`
+}
+
+// indexEntry describes one installable collection as published by the hub.
+type indexEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Digest  string `json:"digest"`
+	URL     string `json:"url"`
+}
+
+// CacheDir returns the directory installed collections are cached in,
+// honoring $XDG_DATA_HOME with a fallback to ~/.local/share.
+func CacheDir() (string, error) {
+	if dataHome := os.Getenv("XDG_DATA_HOME"); dataHome != "" {
+		return filepath.Join(dataHome, "diligent", "hub"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "diligent", "hub"), nil
+}
+
+// fetchIndex downloads the bundle index from the remote hub.
+func fetchIndex(remote string) ([]indexEntry, error) {
+	url := fmt.Sprintf("https://raw.githubusercontent.com/%s/main/%s", remote, IndexFile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetch hub index: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch hub index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch hub index: unexpected status %s", resp.Status)
+	}
+
+	var entries []indexEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode hub index: %w", err)
+	}
+	return entries, nil
+}
+
+// verifyDigest checks that data matches the expected SHA256 hex digest.
+func verifyDigest(data []byte, expected string) error {
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if actual != expected {
+		return fmt.Errorf("digest mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// downloadBundle fetches and verifies a single collection bundle.
+func downloadBundle(entry indexEntry) (Collection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, entry.URL, nil)
+	if err != nil {
+		return Collection{}, fmt.Errorf("fetch bundle %s: %w", entry.Name, err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return Collection{}, fmt.Errorf("fetch bundle %s: %w", entry.Name, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Collection{}, fmt.Errorf("read bundle %s: %w", entry.Name, err)
+	}
+
+	if err := verifyDigest(data, entry.Digest); err != nil {
+		return Collection{}, fmt.Errorf("bundle %s: %w", entry.Name, err)
+	}
+
+	var col Collection
+	if err := yaml.Unmarshal(data, &col); err != nil {
+		return Collection{}, fmt.Errorf("parse bundle %s: %w", entry.Name, err)
+	}
+	col.Name = entry.Name
+	col.Version = entry.Version
+	col.Digest = entry.Digest
+	for i := range col.Checks {
+		col.Checks[i].Collection = col.Name
+	}
+	return col, nil
+}
+
+// collectionPath returns the cache path a collection is stored at.
+func collectionPath(cacheDir, name string) string {
+	return filepath.Join(cacheDir, name+".yaml")
+}
+
+// writeCollection persists a collection to the cache as YAML.
+func writeCollection(cacheDir string, col Collection) error {
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	data, err := yaml.Marshal(col)
+	if err != nil {
+		return fmt.Errorf("marshal collection %s: %w", col.Name, err)
+	}
+	return os.WriteFile(collectionPath(cacheDir, col.Name), data, 0644)
+}
+
+// readCollection loads a previously installed collection from the cache.
+func readCollection(cacheDir, name string) (Collection, error) {
+	data, err := os.ReadFile(collectionPath(cacheDir, name))
+	if err != nil {
+		return Collection{}, fmt.Errorf("read collection %s: %w", name, err)
+	}
+	var col Collection
+	if err := yaml.Unmarshal(data, &col); err != nil {
+		return Collection{}, fmt.Errorf("parse collection %s: %w", name, err)
+	}
+	return col, nil
+}
+
+// installedCollections lists the names of collections present in the cache.
+func installedCollections(cacheDir string) ([]string, error) {
+	entries, err := os.ReadDir(cacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache dir: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".yaml" {
+			continue
+		}
+		names = append(names, e.Name()[:len(e.Name())-len(".yaml")])
+	}
+	return names, nil
+}
+
+// LoadInstalled returns every cached check that targets the given OS
+// ("macOS", "Linux" or "Windows"), across all installed collections.
+func LoadInstalled(targetOS string) ([]Check, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	names, err := installedCollections(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var checks []Check
+	for _, name := range names {
+		col, err := readCollection(cacheDir, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, chk := range col.Checks {
+			if chk.OS == targetOS {
+				checks = append(checks, chk)
+			}
+		}
+	}
+	return checks, nil
+}
@@ -0,0 +1,27 @@
+package hub
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestVerifyDigest(t *testing.T) {
+	data := []byte("name: persistence\nversion: 1.0.0\nchecks: []\n")
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	if err := verifyDigest(data, digest); err != nil {
+		t.Fatalf("expected matching digest to verify, got %v", err)
+	}
+
+	if err := verifyDigest(data, "0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
+		t.Fatal("expected a mismatched digest to be rejected")
+	}
+
+	tampered := append([]byte(nil), data...)
+	tampered[0] ^= 0xff
+	if err := verifyDigest(tampered, digest); err == nil {
+		t.Fatal("expected tampered content to fail its original digest")
+	}
+}
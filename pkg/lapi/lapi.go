@@ -0,0 +1,112 @@
+// Package lapi implements a local-API subsystem so a central diligent
+// instance can aggregate reports pushed by many scanner agents, modeled
+// after CrowdSec's agent/LAPI split. A single binary can act purely as an
+// agent (push reports), purely as a LAPI (receive and store them), or both.
+package lapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// requestTimeout bounds how long a single LAPI HTTP call is allowed to
+// take, so a register or report push can't hang forever against an
+// unreachable LAPI instance.
+const requestTimeout = 10 * time.Second
+
+// NewBootstrapToken generates a random, single-use enrollment token for
+// `diligent machines add <name>`.
+func NewBootstrapToken() (string, error) {
+	return randomToken(16)
+}
+
+// NewAPIKey generates a long-lived key handed to an agent once it
+// completes enrollment.
+func NewAPIKey() (string, error) {
+	return randomToken(32)
+}
+
+func randomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// RegisterRequest is sent by an agent exchanging its bootstrap token for a
+// long-lived API key.
+type RegisterRequest struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+// RegisterResponse carries the API key issued to a newly enrolled agent.
+type RegisterResponse struct {
+	APIKey string `json:"api_key"`
+}
+
+// Register exchanges a bootstrap token for a long-lived API key against a
+// LAPI instance at url, as used by `diligent lapi register`.
+func Register(url, name, token string) (string, error) {
+	reqBody, err := json.Marshal(RegisterRequest{Name: name, Token: token})
+	if err != nil {
+		return "", fmt.Errorf("marshal register request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/v1/register", bytes.NewReader(reqBody))
+	if err != nil {
+		return "", fmt.Errorf("build register request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("register with LAPI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("register with LAPI: unexpected status %s", resp.Status)
+	}
+
+	var out RegisterResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("decode register response: %w", err)
+	}
+	return out.APIKey, nil
+}
+
+// PushReport submits a marshalled report to a LAPI instance at url,
+// authenticating with the machine's long-lived API key.
+func PushReport(url, apiKey string, report []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url+"/v1/reports", bytes.NewReader(report))
+	if err != nil {
+		return fmt.Errorf("build report request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("push report to LAPI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("push report to LAPI: unexpected status %s: %s", resp.Status, body)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package lapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/audi70r/scanner-darkly/db"
+	"github.com/audi70r/scanner-darkly/pkg/scan"
+)
+
+// Server is a minimal LAPI HTTP server that receives enrollment requests
+// and report submissions from scanner agents.
+type Server struct {
+	mux *http.ServeMux
+}
+
+// NewServer builds a Server with its routes registered.
+func NewServer() *Server {
+	s := &Server{mux: http.NewServeMux()}
+	s.mux.HandleFunc("/v1/register", s.handleRegister)
+	s.mux.HandleFunc("/v1/reports", s.handleReports)
+	return s
+}
+
+// ListenAndServe starts the LAPI on addr (e.g. ":8080").
+func (s *Server) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, s.mux)
+}
+
+func (s *Server) handleRegister(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req RegisterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	machine, err := db.FindMachineByBootstrapToken(req.Token)
+	if err != nil {
+		http.Error(w, "invalid bootstrap token", http.StatusUnauthorized)
+		return
+	}
+
+	apiKey, err := NewAPIKey()
+	if err != nil {
+		http.Error(w, "failed to generate API key", http.StatusInternalServerError)
+		return
+	}
+
+	if err := db.EnrollMachine(machine.ID, apiKey); err != nil {
+		http.Error(w, "failed to enroll machine", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(RegisterResponse{APIKey: apiKey})
+}
+
+func (s *Server) handleReports(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.createReport(w, r)
+	case http.MethodGet:
+		s.listReports(w, r)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) createReport(w http.ResponseWriter, r *http.Request) {
+	apiKey := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	machine, err := db.FindMachineByAPIKey(apiKey)
+	if err != nil {
+		http.Error(w, "unknown or unenrolled machine", http.StatusUnauthorized)
+		return
+	}
+
+	var report json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+		http.Error(w, "invalid report body", http.StatusBadRequest)
+		return
+	}
+
+	if err := db.CreateMachineLog(time.Now(), string(report), machine.ID); err != nil {
+		http.Error(w, "failed to store report", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) listReports(w http.ResponseWriter, r *http.Request) {
+	machineName := r.URL.Query().Get("machine")
+	flaggedOnly, _ := strconv.ParseBool(r.URL.Query().Get("flagged"))
+
+	var logs []db.Log
+	var err error
+	if machineName != "" {
+		machine, merr := db.FindMachineByName(machineName)
+		if merr != nil {
+			http.Error(w, "unknown machine", http.StatusNotFound)
+			return
+		}
+		logs, err = db.ListMachineLogs(machine.ID)
+	} else {
+		logs, err = db.ListLogs()
+	}
+	if err != nil {
+		http.Error(w, "failed to list reports", http.StatusInternalServerError)
+		return
+	}
+
+	if flaggedOnly {
+		logs = filterFlagged(logs)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(logs)
+}
+
+// filterFlagged keeps only reports that contain at least one flagged
+// analysis item, at any depth of follow-up.
+func filterFlagged(logs []db.Log) []db.Log {
+	kept := make([]db.Log, 0, len(logs))
+	for _, l := range logs {
+		if scan.IsFlagged(l.Content) {
+			kept = append(kept, l)
+		}
+	}
+	return kept
+}
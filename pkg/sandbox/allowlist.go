@@ -0,0 +1,64 @@
+package sandbox
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultAllowlist is the set of binaries a GPT-suggested follow-up
+// command may invoke when a check doesn't declare its own. It's
+// deliberately limited to read-only inspection tools.
+var DefaultAllowlist = []string{
+	"cat", "grep", "awk", "sort", "uniq", "head", "tail", "wc",
+	"ps", "ls", "lsof", "who", "last", "dscl", "launchctl", "kextstat",
+}
+
+// shellMetacharacters matches anything that would let a single "stage"
+// smuggle a second command past the allowlist check: command separators
+// (;, &, &&, ||), pipes, command substitution ($(...), backticks),
+// redirection and newlines.
+var shellMetacharacters = regexp.MustCompile("[;&|`$<>\n\r]")
+
+// safeArg matches a single shell word that can't itself break out into a
+// new command: no quoting, no globbing, no substitution.
+var safeArg = regexp.MustCompile(`^[A-Za-z0-9_./=:@%+,-]+$`)
+
+// ValidateFollowUp checks a GPT-suggested follow-up command against
+// allowlist - the per-check allowlist of binaries a follow-up may invoke,
+// falling back to DefaultAllowlist when a check doesn't declare one. The
+// command must be a single invocation of an allowed binary; any shell
+// metacharacter (so no "&&", "|", "$(...)", backticks, redirection, ...)
+// or argument outside a conservative safe pattern is rejected outright,
+// rather than only checking the first word of the first pipe stage.
+func ValidateFollowUp(command string, allowlist []string) error {
+	if shellMetacharacters.MatchString(command) {
+		return fmt.Errorf("follow-up command contains disallowed shell metacharacters")
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return fmt.Errorf("empty follow-up command")
+	}
+
+	if allowlist == nil {
+		allowlist = DefaultAllowlist
+	}
+	allowed := make(map[string]bool, len(allowlist))
+	for _, b := range allowlist {
+		allowed[b] = true
+	}
+
+	binary := fields[0]
+	if !allowed[binary] {
+		return fmt.Errorf("binary %q is not in the follow-up allowlist", binary)
+	}
+
+	for _, arg := range fields[1:] {
+		if !safeArg.MatchString(arg) {
+			return fmt.Errorf("argument %q is not in the allowed argument pattern", arg)
+		}
+	}
+
+	return nil
+}
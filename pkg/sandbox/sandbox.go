@@ -0,0 +1,130 @@
+// Package sandbox executes check commands under a restricted profile
+// instead of plain `sh -c`, since commands are partly directed by GPT
+// follow-ups and therefore shouldn't run with the full privileges of the
+// caller. On Linux it wraps the command with bubblewrap; on macOS it
+// generates a sandbox-exec profile. On both, if diligent is running as
+// root it drops to an unprivileged uid before exec'ing the command.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// unprivilegedUID is the uid diligent drops to before running a check when
+// it is itself running as root. "nobody" is 65534 on every platform we
+// target.
+const unprivilegedUID = 65534
+
+// Run executes command under a sandbox profile built from capabilities,
+// honoring timeout, and returns its combined output.
+func Run(ctx context.Context, command string, capabilities []string, timeout time.Duration) (string, error) {
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := wrap(runCtx, command, capabilities)
+	dropPrivileges(cmd)
+
+	output, err := cmd.CombinedOutput()
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		return "", errors.New("command execution timed out")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to execute command: %s, error: %w", command, err)
+	}
+
+	return string(output), nil
+}
+
+// wrap builds the *exec.Cmd that runs command under the sandbox profile
+// appropriate for the current OS.
+func wrap(ctx context.Context, command string, capabilities []string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "linux":
+		return exec.CommandContext(ctx, "bwrap", append(bubblewrapArgs(capabilities), "sh", "-c", command)...)
+	case "darwin":
+		return exec.CommandContext(ctx, "sandbox-exec", "-p", sandboxExecProfile(capabilities), "sh", "-c", command)
+	default:
+		return exec.CommandContext(ctx, "sh", "-c", command)
+	}
+}
+
+// bubblewrapArgs translates Capabilities into bubblewrap flags: a fresh
+// namespace with read-only binds for anything the check needs to read,
+// a throwaway tmpfs for everything writable, and network/process
+// namespaces dropped unless explicitly requested.
+func bubblewrapArgs(capabilities []string) []string {
+	args := []string{
+		"--ro-bind", "/usr", "/usr",
+		"--ro-bind", "/bin", "/bin",
+		"--ro-bind", "/lib", "/lib",
+		"--proc", "/proc",
+		"--tmpfs", "/tmp",
+		"--die-with-parent",
+	}
+
+	if !has(capabilities, "net") {
+		args = append(args, "--unshare-net")
+	}
+	if !has(capabilities, "proc") {
+		args = append(args, "--unshare-pid")
+	}
+
+	for _, cap := range capabilities {
+		if path, ok := strings.CutPrefix(cap, "fs-read:"); ok {
+			args = append(args, "--ro-bind", path, path)
+		}
+	}
+
+	return args
+}
+
+// sandboxExecProfile renders a minimal macOS Seatbelt profile that denies
+// everything by default, then allows process execution and file reads
+// everywhere, plus network access for "net" and IOKit access for "usb".
+// File reads are not restricted on macOS: the "fs-read:" capabilities
+// only add rules for paths that are already readable.
+func sandboxExecProfile(capabilities []string) string {
+	var b strings.Builder
+	b.WriteString("(version 1)\n(deny default)\n(allow process-exec)\n(allow file-read*)\n")
+
+	if has(capabilities, "net") {
+		b.WriteString("(allow network*)\n")
+	}
+	if has(capabilities, "usb") {
+		b.WriteString("(allow iokit-open)\n")
+	}
+	for _, cap := range capabilities {
+		if path, ok := strings.CutPrefix(cap, "fs-read:"); ok {
+			fmt.Fprintf(&b, "(allow file-read* (subpath %q))\n", path)
+		}
+	}
+
+	return b.String()
+}
+
+// dropPrivileges sets the child process's uid to an unprivileged one when
+// diligent itself is running as root.
+func dropPrivileges(cmd *exec.Cmd) {
+	if syscall.Geteuid() != 0 {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: unprivilegedUID, Gid: unprivilegedUID},
+	}
+}
+
+func has(capabilities []string, name string) bool {
+	for _, c := range capabilities {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/audi70r/scanner-darkly/pkg/chunker"
+)
+
+func TestChunkAnalysesRecordsSelectedAndDropped(t *testing.T) {
+	analyses := chunkAnalyses(
+		[]chunker.Chunk{{Index: 0, Score: 5}, {Index: 1, Score: 1}},
+		[]chunker.Chunk{{Index: 0, Score: 5}},
+	)
+
+	if len(analyses) != 2 {
+		t.Fatalf("expected an entry for every chunk, got %d", len(analyses))
+	}
+	if !analyses[0].Selected {
+		t.Errorf("expected chunk 0 to be marked selected")
+	}
+	if analyses[1].Selected {
+		t.Errorf("expected chunk 1 (dropped by scoring) to be marked not selected")
+	}
+	if analyses[1].Score != 1 {
+		t.Errorf("expected the dropped chunk's score to still be recorded, got %v", analyses[1].Score)
+	}
+}
+
+func TestMarkLikelyCulpritFlagsOnlyHighestScoringSelectedChunk(t *testing.T) {
+	analyses := []ChunkAnalysis{
+		{Index: 0, Score: 2, Selected: true},
+		{Index: 1, Score: 8, Selected: true},
+		{Index: 2, Score: 9, Selected: false},
+	}
+
+	markLikelyCulprit(analyses)
+
+	if analyses[0].Flagged {
+		t.Errorf("expected the lower-scoring selected chunk to stay unflagged")
+	}
+	if !analyses[1].Flagged {
+		t.Errorf("expected the highest-scoring selected chunk to be flagged")
+	}
+	if analyses[2].Flagged {
+		t.Errorf("expected a non-selected chunk to never be flagged, even with the highest score")
+	}
+}
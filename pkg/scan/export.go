@@ -0,0 +1,115 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sarifLog is a minimal SARIF 2.1.0 document: enough for `report export
+// --format sarif` to produce something CI tooling can ingest.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID  string       `json:"ruleId"`
+	Level   string       `json:"level"`
+	Message sarifMessage `json:"message"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+// Export renders a Report in the requested format: "json", "yaml",
+// "sarif" or "html".
+func Export(report Report, format string) ([]byte, error) {
+	switch format {
+	case "", "json":
+		return json.MarshalIndent(report, "", "  ")
+	case "yaml":
+		return yaml.Marshal(report)
+	case "sarif":
+		return exportSARIF(report)
+	case "html":
+		return exportHTML(report), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q", format)
+	}
+}
+
+func exportSARIF(report Report) ([]byte, error) {
+	var results []sarifResult
+	for _, item := range flatten(report.Items) {
+		if !item.Flagged {
+			continue
+		}
+		results = append(results, sarifResult{
+			RuleID: item.Command,
+			Level:  "warning",
+			Message: sarifMessage{
+				Text: item.AnalysisDescription,
+			},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool:    sarifTool{Driver: sarifDriver{Name: "diligent"}},
+				Results: results,
+			},
+		},
+	}
+	return json.MarshalIndent(log, "", "  ")
+}
+
+func exportHTML(report Report) []byte {
+	var b strings.Builder
+	b.WriteString("<!doctype html><html><head><meta charset=\"utf-8\"><title>diligent report</title></head><body>\n")
+	b.WriteString("<h1>diligent report</h1>\n<ul>\n")
+	for _, item := range flatten(report.Items) {
+		status := "ok"
+		if item.Flagged {
+			status = "flagged"
+		}
+		fmt.Fprintf(&b, "<li><strong>[%s]</strong> <code>%s</code>: %s</li>\n", status, htmlEscape(item.Command), htmlEscape(item.AnalysisDescription))
+	}
+	b.WriteString("</ul>\n</body></html>\n")
+	return []byte(b.String())
+}
+
+// flatten walks an AnalysisItem tree (including follow-ups) into a flat slice.
+func flatten(items []AnalysisItem) []AnalysisItem {
+	var out []AnalysisItem
+	for _, item := range items {
+		out = append(out, item)
+		out = append(out, flatten(item.FollowUps)...)
+	}
+	return out
+}
+
+func htmlEscape(s string) string {
+	replacer := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	return replacer.Replace(s)
+}
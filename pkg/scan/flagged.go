@@ -0,0 +1,28 @@
+package scan
+
+import "encoding/json"
+
+// IsFlagged reports whether a stored report (as produced by Run and
+// persisted verbatim as db.Log.Content) contains at least one flagged
+// analysis item, at any depth of follow-up. Content that isn't a valid
+// Report is treated as not flagged rather than an error, since callers
+// use this purely to filter a list of logs.
+func IsFlagged(content string) bool {
+	var report Report
+	if err := json.Unmarshal([]byte(content), &report); err != nil {
+		return false
+	}
+	return anyFlagged(report.Items)
+}
+
+func anyFlagged(items []AnalysisItem) bool {
+	for _, item := range items {
+		if item.Flagged {
+			return true
+		}
+		if anyFlagged(item.FollowUps) {
+			return true
+		}
+	}
+	return false
+}
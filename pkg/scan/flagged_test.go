@@ -0,0 +1,25 @@
+package scan
+
+import "testing"
+
+func TestIsFlagged(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"top-level flagged", `{"items":[{"flagged":true}]}`, true},
+		{"nothing flagged", `{"items":[{"flagged":false}]}`, false},
+		{"flagged follow-up only", `{"items":[{"flagged":false,"follow_ups":[{"flagged":true}]}]}`, true},
+		{"empty report", `{"items":[]}`, false},
+		{"not valid json", `not json`, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsFlagged(tc.content); got != tc.want {
+				t.Errorf("IsFlagged(%q) = %v, want %v", tc.content, got, tc.want)
+			}
+		})
+	}
+}
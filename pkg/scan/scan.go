@@ -0,0 +1,328 @@
+// Package scan runs a set of hub checks through GPT analysis and produces
+// a Report. It holds the core domain logic that used to live directly in
+// main.go, now shared between the `run` command and anything else that
+// needs to replay or re-render a report (e.g. `report export`).
+package scan
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+
+	"github.com/audi70r/scanner-darkly/pkg/chunker"
+	hostcontext "github.com/audi70r/scanner-darkly/pkg/context"
+	"github.com/audi70r/scanner-darkly/pkg/hub"
+	"github.com/audi70r/scanner-darkly/pkg/sandbox"
+)
+
+// GPTResponse is the structured JSON response expected from the OpenAI API.
+type GPTResponse struct {
+	Flagged         bool   `json:"flagged"`
+	Description     string `json:"description"`
+	FollowUpPrompt  string `json:"follow_up_prompt,omitempty"`
+	FollowUpCommand string `json:"follow_up_command,omitempty"`
+	Alert           string `json:"alert,omitempty"`
+}
+
+// ChunkAnalysis records why a chunk of command output was or wasn't sent
+// to GPT, so operators can audit the decision after the fact. Every chunk
+// produced by chunker.Split gets an entry, not just the ones selected for
+// analysis, so a low-scoring chunk's score is still on record. Flagged is
+// only ever set on the selected chunk GPT's verdict is attributed to -
+// GPT returns one aggregate decision for the whole prompt, not a
+// per-chunk one, so this is a score-based best guess at which chunk
+// drove it, not a guarantee.
+type ChunkAnalysis struct {
+	Index    int     `json:"index"`
+	Score    float64 `json:"score"`
+	Selected bool    `json:"selected"`
+	Flagged  bool    `json:"flagged"`
+}
+
+// AnalysisItem holds all information about one analysis step (initial or follow-up).
+type AnalysisItem struct {
+	Prompt              string            `json:"prompt,omitempty"`
+	Command             string            `json:"command,omitempty"`
+	Flagged             bool              `json:"flagged"`
+	AnalysisDescription string            `json:"analysis_description,omitempty"`
+	Alert               string            `json:"alert,omitempty"`
+	RawOutput           string            `json:"raw_output,omitempty"`
+	Context             map[string]string `json:"context,omitempty"`
+	Chunks              []ChunkAnalysis   `json:"chunks,omitempty"`
+	FollowUps           []AnalysisItem    `json:"follow_ups,omitempty"`
+}
+
+// Report is the top-level result of a run.
+type Report struct {
+	Items []AnalysisItem `json:"items"`
+}
+
+// Options configures a Run.
+type Options struct {
+	Model          string
+	MaxFollowups   int
+	CommandTimeout time.Duration
+	ContextConfig  hostcontext.Config
+}
+
+// DetectOS returns the OS name diligent's checks and prompts key off of.
+func DetectOS() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS"
+	case "linux":
+		return "Linux"
+	case "windows":
+		return "Windows"
+	default:
+		return "Unsupported OS"
+	}
+}
+
+// Run executes every check and returns the resulting Report.
+func Run(client *openai.Client, checks []hub.Check, opts Options) Report {
+	compiledContext := hostcontext.Gather().Compile()
+
+	results := make([]AnalysisItem, 0, len(checks))
+	for _, chk := range checks {
+		checkCtx := hostcontext.Subset(compiledContext, opts.ContextConfig.KeysForTags(chk.Tags))
+		allowlist := chk.Allowlist
+		if len(allowlist) == 0 {
+			allowlist = sandbox.DefaultAllowlist
+		}
+		item, err := analyze(client, chk.Prompt, chk.Command, 0, checkCtx, chk.Capabilities, allowlist, opts)
+		if err != nil {
+			fmt.Printf("Error analyzing command %s: %v\n", chk.Command, err)
+			continue
+		}
+		results = append(results, item)
+	}
+
+	return Report{Items: results}
+}
+
+// callOpenAI sends a prompt to the OpenAI ChatCompletion API and returns a GPTResponse.
+func callOpenAI(client *openai.Client, prompt, model string, hostCtx map[string]string) (GPTResponse, error) {
+	os := DetectOS()
+
+	systemMessage := `You are a careful and accurate system analyst. Our operating system is ` + os + `.
+Your task is to evaluate the provided command output in the context of the given prompt and determine if there is any truly suspicious activity. If you identify any issues, set flagged to true, provide a description of the problem and a follow up GPT prompt terminal command to analyse it in a more detailed way.
+Return data as JSON:
+{
+  "flagged": boolean,
+  "description": string,
+  "follow_up_prompt": string,
+  "follow_up_command": string,
+  "alert": string
+}
+Do not include any extra text outside of the JSON object.`
+
+	messages := []openai.ChatCompletionMessage{
+		{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: systemMessage,
+		},
+	}
+	if len(hostCtx) > 0 {
+		messages = append(messages, openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleSystem,
+			Content: "Known host context, use it to avoid flagging expected activity:\n" + formatHostContext(hostCtx),
+		})
+	}
+	messages = append(messages, openai.ChatCompletionMessage{
+		Role:    openai.ChatMessageRoleUser,
+		Content: prompt,
+	})
+
+	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
+		MaxTokens: 8100,
+		Model:     model,
+		Messages:  messages,
+	})
+	if err != nil {
+		return GPTResponse{}, err
+	}
+
+	var gptResp GPTResponse
+	err = json.Unmarshal([]byte(resp.Choices[0].Message.Content), &gptResp)
+	if err != nil {
+		return GPTResponse{}, fmt.Errorf("failed to parse GPT response: %w", err)
+	}
+
+	return gptResp, nil
+}
+
+// formatHostContext renders a host context subset as "key: value" lines.
+func formatHostContext(hostCtx map[string]string) string {
+	var b strings.Builder
+	for k, v := range hostCtx {
+		fmt.Fprintf(&b, "%s: %s\n", k, v)
+	}
+	return b.String()
+}
+
+// chunkSize is the maximum size of a single chunk of command output, and
+// maxChunks is how many of the highest-scoring chunks get sent to GPT.
+const (
+	chunkSize = 1500
+	maxChunks = 4
+)
+
+// selectChunks scores every chunk of command output against the check's
+// own prompt and returns both the highest-signal chunks (selected, sent
+// to GPT) and the full scored set (all, for the audit trail), instead of
+// hard-truncating at a fixed character count and silently dropping
+// everything after it.
+func selectChunks(output, prompt string) (selected, all []chunker.Chunk) {
+	all = chunker.ScoreAll(output, chunker.Keywords(prompt), chunkSize)
+	selected = chunker.SelectTopK(all, maxChunks)
+	return selected, all
+}
+
+// renderChunks joins the selected chunks into a single prompt body with
+// clear separators.
+func renderChunks(selected []chunker.Chunk) string {
+	var parts []string
+	for _, c := range selected {
+		parts = append(parts, fmt.Sprintf("--- chunk %d (relevance %.2f) ---\n%s", c.Index, c.Score, c.Text))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// chunkAnalyses builds one ChunkAnalysis per chunk in all, recording
+// whether each was selected for analysis.
+func chunkAnalyses(all, selected []chunker.Chunk) []ChunkAnalysis {
+	selectedIdx := make(map[int]bool, len(selected))
+	for _, c := range selected {
+		selectedIdx[c.Index] = true
+	}
+	analyses := make([]ChunkAnalysis, 0, len(all))
+	for _, c := range all {
+		analyses = append(analyses, ChunkAnalysis{Index: c.Index, Score: c.Score, Selected: selectedIdx[c.Index]})
+	}
+	return analyses
+}
+
+// markLikelyCulprit flags the highest-scoring selected chunk as the one
+// that most likely drove a flagged verdict. GPT returns a single
+// aggregate decision for the whole merged prompt rather than a per-chunk
+// one, so this is a score-based rationale rather than a real per-chunk
+// verdict.
+func markLikelyCulprit(analyses []ChunkAnalysis) {
+	best := -1
+	for i, c := range analyses {
+		if !c.Selected {
+			continue
+		}
+		if best == -1 || c.Score > analyses[best].Score {
+			best = i
+		}
+	}
+	if best >= 0 {
+		analyses[best].Flagged = true
+	}
+}
+
+// analyze handles the analysis of a command and any follow-ups it generates.
+// hostCtx is the host context subset selected for the originating check and
+// is reused unchanged for any follow-ups it spawns. capabilities and
+// allowlist constrain what the command (and any follow-up GPT suggests)
+// is permitted to do; see pkg/sandbox.
+func analyze(client *openai.Client, prompt, command string, depth int, hostCtx map[string]string, capabilities, allowlist []string, opts Options) (AnalysisItem, error) {
+	item := AnalysisItem{
+		Prompt:  prompt,
+		Command: command,
+		Context: hostCtx,
+	}
+
+	// If no command, don't do anything
+	if command == "" {
+		item.Flagged = false
+		item.AnalysisDescription = "No command provided"
+		return item, nil
+	}
+
+	if depth > 0 {
+		if err := sandbox.ValidateFollowUp(command, allowlist); err != nil {
+			item.Flagged = false
+			item.Alert = "sandbox denied"
+			item.AnalysisDescription = err.Error()
+			return item, nil
+		}
+	}
+
+	fmt.Printf("%sExecuting command: %s\n", indent(depth), command)
+	commandOutput, err := sandbox.Run(context.Background(), command, capabilities, opts.CommandTimeout)
+	if err != nil {
+		item.Flagged = false
+		item.AnalysisDescription = fmt.Sprintf("Command execution error: %v", err)
+		item.RawOutput = commandOutput
+		return item, nil
+	}
+
+	// Select the most relevant chunks of output instead of hard-truncating,
+	// but keep every chunk's score on record for the audit trail.
+	selected, allChunks := selectChunks(commandOutput, prompt)
+	renderedChunks := renderChunks(selected)
+	analyses := chunkAnalyses(allChunks, selected)
+	fullPrompt := fmt.Sprintf("%s\n\nCommand output (%d most relevant chunks):\n%s", command, len(selected), renderedChunks)
+
+	// Analyze with OpenAI
+	response, err := callOpenAI(client, fullPrompt, opts.Model, hostCtx)
+	if err != nil {
+		item.Flagged = false
+		item.AnalysisDescription = fmt.Sprintf("OpenAI API error: %v", err)
+		item.RawOutput = renderedChunks
+		item.Chunks = analyses
+		return item, nil
+	}
+
+	// Set item fields from response
+	item.Flagged = response.Flagged
+	item.AnalysisDescription = response.Description
+	item.Alert = response.Alert
+	item.RawOutput = renderedChunks
+	if response.Flagged {
+		markLikelyCulprit(analyses)
+	}
+	item.Chunks = analyses
+
+	// Handle multiple follow-ups if suggested
+	currentResponse := response
+	currentDepth := depth
+	for item.Flagged && currentResponse.FollowUpCommand != "" && currentResponse.FollowUpPrompt != "" && currentDepth < opts.MaxFollowups {
+		currentDepth++
+		fmt.Printf("%sExecuting follow-up command: %s\n", indent(currentDepth), currentResponse.FollowUpCommand)
+		followupItem, err := analyze(client, currentResponse.FollowUpPrompt, currentResponse.FollowUpCommand, currentDepth, hostCtx, capabilities, allowlist, opts)
+		if err != nil {
+			// If follow-up fails, break out
+			break
+		}
+		// Append the follow-up result
+		item.FollowUps = append(item.FollowUps, followupItem)
+
+		// If the follow-up item is flagged and suggests another follow-up, continue
+		if len(followupItem.FollowUps) > 0 {
+			// If that follow-up had further follow-ups, they are already included in followupItem.FollowUps
+			// We break here since analyze() recursively handled deeper follow-ups.
+			break
+		}
+
+		// Otherwise, if this follow-up also suggests another follow-up, re-analyze with its response
+		// Since `followupItem` is a fully processed item including nested follow-ups, there's no need
+		// to loop again at this level.
+		break
+	}
+
+	return item, nil
+}
+
+// indent returns a string of spaces for pretty-printing at given depth.
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}